@@ -0,0 +1,139 @@
+package cclib
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const testCaBundle = `-----BEGIN CERTIFICATE-----
+MIIBQzCB6qADAgECAgEBMAoGCCqGSM49BAMCMBIxEDAOBgNVBAMTB3Rlc3QtY2Ew
+HhcNMjYwNzI3MDQxNTE0WhcNMjYwNzI4MDQxNTE0WjASMRAwDgYDVQQDEwd0ZXN0
+LWNhMFkwEwYHKoZIzj0CAQYIKoZIzj0DAQcDQgAEtCI2AwpX9gcSf41m2XSn0dYT
+fos+Kb5JakvP4Z2qN676vTmXKpfKR5NUxiRloYbQzo9A/Xrx+Ot/6k+Wt38J56Mx
+MC8wDgYDVR0PAQH/BAQDAgIEMB0GA1UdDgQWBBSRZEZNZN1FsJCL5COptaeCunsP
+GzAKBggqhkjOPQQDAgNIADBFAiBoQFAQosgCwnO3gSWj00sjGqD+ZQR6oNXURWSK
+0N1EnwIhALbaa+TOx/X62GbzuaNZdkWVdBjPnsNMGygiXUDp4Gji
+-----END CERTIFICATE-----
+`
+
+func TestParseBootstrapToken(t *testing.T) {
+	// Given
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cacerts" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprint(w, testCaBundle)
+	}))
+	defer server.Close()
+
+	sum := sha256.Sum256([]byte(testCaBundle))
+	hash := hex.EncodeToString(sum[:])
+	token := BootstrapTokenPrefix + hash + "::user@example.com:password"
+
+	// When
+	req, err := ParseBootstrapToken(server.URL, token)
+
+	// Then
+	if err != nil {
+		t.Fatalf("ParseBootstrapToken failed: %v", err)
+	}
+	if req.Email != "user@example.com" {
+		t.Errorf(msgFail, "ParseBootstrapToken and Email", "user@example.com", req.Email)
+	}
+	if req.Password != "password" {
+		t.Errorf(msgFail, "ParseBootstrapToken and Password", "password", req.Password)
+	}
+	if req.CaCerts == nil {
+		t.Errorf(msgFail, "ParseBootstrapToken and CaCerts", "non-nil pool", "nil")
+	}
+}
+
+func TestParseBootstrapTokenHashMismatch(t *testing.T) {
+	// Given
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testCaBundle)
+	}))
+	defer server.Close()
+
+	token := BootstrapTokenPrefix + "deadbeef::user@example.com:password"
+
+	// When
+	_, err := ParseBootstrapToken(server.URL, token)
+
+	// Then
+	if err == nil {
+		t.Error("ParseBootstrapToken should fail on CA bundle fingerprint mismatch")
+	}
+}
+
+func TestBootstrapTokenRoundTrip(t *testing.T) {
+	// Given
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, testCaBundle)
+	}))
+	defer server.Close()
+
+	sum := sha256.Sum256([]byte(testCaBundle))
+	hash := hex.EncodeToString(sum[:])
+	token := BootstrapTokenPrefix + hash + "::user@example.com:password"
+
+	req, err := ParseBootstrapToken(server.URL, token)
+	if err != nil {
+		t.Fatalf("ParseBootstrapToken failed: %v", err)
+	}
+
+	// When
+	rendered, err := req.BootstrapToken()
+
+	// Then
+	if err != nil {
+		t.Fatalf("BootstrapToken failed: %v", err)
+	}
+	if rendered != token {
+		t.Errorf(msgFail, "BootstrapToken", token, rendered)
+	}
+}
+
+func TestBootstrapTokenRequiresPEMSnapshot(t *testing.T) {
+	// Given
+	req := NewRequest("user@example.com", "password", "https://api.com", nil, "")
+	pool := x509.NewCertPool()
+	req.SetCaCerts(pool)
+
+	// When
+	_, err := req.BootstrapToken()
+
+	// Then
+	if err == nil {
+		t.Error("BootstrapToken should fail when request has no CA bundle PEM snapshot")
+	}
+}
+
+func TestSetCaCertsFromPEMEnablesBootstrapToken(t *testing.T) {
+	// Given
+	req := NewRequest("user@example.com", "password", "https://api.com", nil, "")
+
+	// When
+	err := req.SetCaCertsFromPEM([]byte(testCaBundle))
+	if err != nil {
+		t.Fatalf("SetCaCertsFromPEM failed: %v", err)
+	}
+	token, err := req.BootstrapToken()
+
+	// Then
+	if err != nil {
+		t.Fatalf("BootstrapToken failed: %v", err)
+	}
+	sum := sha256.Sum256([]byte(testCaBundle))
+	wantHash := hex.EncodeToString(sum[:])
+	wantToken := BootstrapTokenPrefix + wantHash + "::user@example.com:password"
+	if token != wantToken {
+		t.Errorf(msgFail, "BootstrapToken", wantToken, token)
+	}
+}
@@ -0,0 +1,39 @@
+package cclib
+
+import (
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+)
+
+// VERSION is the version of gocclib
+const VERSION = "0.2.2"
+
+// CACHE is the default cache directory used by a Request
+const CACHE = "~/.cache/cloudControl"
+
+// SSL_CHECK is the default value for Request.SslCheck
+const SSL_CHECK = true
+
+// DEBUG toggles verbose request/response logging
+const DEBUG = false
+
+// CA_CERTS is the default root CA pool used by a Request. A nil pool
+// makes the http.Transport fall back to the system root CAs.
+var CA_CERTS *x509.CertPool
+
+// Version returns the version of gocclib
+func Version() string {
+	return VERSION
+}
+
+// checkResponse returns an *APIError if resp does not carry a successful
+// status code
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	return newAPIError(resp, body)
+}
@@ -0,0 +1,9 @@
+package cclib
+
+// msgFail is the shared assertion message format used across the test
+// suite
+const msgFail = "%s failed, expected: '%v', got: '%v'"
+
+// API_URL is a placeholder API URL used by tests that need to build a
+// request without talking to a real server
+const API_URL = "https://api.cloudcontrolled.com"
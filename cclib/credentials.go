@@ -0,0 +1,100 @@
+package cclib
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+)
+
+const (
+	envEmail    = "CCLIB_EMAIL"
+	envPassword = "CCLIB_PASSWORD"
+	envToken    = "CCLIB_TOKEN"
+	envAPIUrl   = "CCLIB_API_URL"
+	envTokenUrl = "CCLIB_TOKEN_URL"
+	envCABundle = "CCLIB_CA_BUNDLE"
+)
+
+// ResolveCredentials fills in request.Email, request.Password and
+// request.Token, in priority order, from: their current (explicitly
+// set) values, the userinfo embedded in request.Url (stripped from the
+// stored URL once read), and the CCLIB_EMAIL, CCLIB_PASSWORD and
+// CCLIB_TOKEN environment variables.
+func (request *Request) ResolveCredentials() error {
+	if err := request.resolveCredentialsFromURL(); err != nil {
+		return err
+	}
+
+	if request.Email == "" {
+		request.Email = os.Getenv(envEmail)
+	}
+	if request.Password == "" {
+		request.Password = os.Getenv(envPassword)
+	}
+	if request.Token == nil {
+		if tokenKey := os.Getenv(envToken); tokenKey != "" {
+			request.Token = &Token{TokenKey: tokenKey}
+		}
+	}
+
+	return nil
+}
+
+// resolveCredentialsFromURL fills in whichever of request.Email and
+// request.Password is not already set from the userinfo part of
+// request.Url, if any, and always strips that userinfo from the stored
+// URL so it is never left lying around unused (e.g. in DEBUG dumps)
+func (request *Request) resolveCredentialsFromURL() error {
+	if request.Url == "" {
+		return nil
+	}
+
+	u, err := url.Parse(request.Url)
+	if err != nil {
+		return err
+	}
+
+	if u.User == nil {
+		return nil
+	}
+
+	if request.Email == "" {
+		request.Email = u.User.Username()
+	}
+	if request.Password == "" {
+		if password, ok := u.User.Password(); ok {
+			request.Password = password
+		}
+	}
+
+	u.User = nil
+	request.Url = u.String()
+	return nil
+}
+
+// NewRequestFromEnv builds a Request purely from environment variables:
+// CCLIB_API_URL, CCLIB_TOKEN_URL and CCLIB_CA_BUNDLE (a path to a PEM
+// file), plus the credentials resolved by ResolveCredentials. This
+// mirrors the twelve-factor pattern used by other Go cloud clients.
+func NewRequestFromEnv() (*Request, error) {
+	apiUrl := os.Getenv(envAPIUrl)
+	if apiUrl == "" {
+		return nil, fmt.Errorf("cclib: %s is not set", envAPIUrl)
+	}
+
+	request := NewRequest("", "", apiUrl, nil, os.Getenv(envTokenUrl))
+
+	if bundle := os.Getenv(envCABundle); bundle != "" {
+		pemBundle, err := ioutil.ReadFile(bundle)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := request.SetCaCertsFromPEM(pemBundle); err != nil {
+			return nil, fmt.Errorf("cclib: loading %s: %w", bundle, err)
+		}
+	}
+
+	return request, nil
+}
@@ -0,0 +1,142 @@
+package cclib
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewRequestResolvesCredentialsFromURL(t *testing.T) {
+	// When
+	req := NewRequest("", "", "https://user@example.com:s3cr3t@api.com", nil, "")
+
+	// Then
+	if req.Email != "user@example.com" {
+		t.Errorf(msgFail, "resolved Email", "user@example.com", req.Email)
+	}
+	if req.Password != "s3cr3t" {
+		t.Errorf(msgFail, "resolved Password", "s3cr3t", req.Password)
+	}
+	if req.Url != "https://api.com" {
+		t.Errorf(msgFail, "stripped Url", "https://api.com", req.Url)
+	}
+}
+
+func TestNewRequestExplicitCredentialsTakePriority(t *testing.T) {
+	// When
+	req := NewRequest("explicit@example.com", "explicit-password", "https://user@api.com", nil, "")
+
+	// Then
+	if req.Email != "explicit@example.com" {
+		t.Errorf(msgFail, "explicit Email", "explicit@example.com", req.Email)
+	}
+	if req.Password != "explicit-password" {
+		t.Errorf(msgFail, "explicit Password", "explicit-password", req.Password)
+	}
+	// the URL's userinfo is unused once both fields are explicitly set,
+	// so it must still be stripped rather than left lying around
+	if req.Url != "https://api.com" {
+		t.Errorf(msgFail, "stripped Url", "https://api.com", req.Url)
+	}
+}
+
+func TestNewRequestFillsOnlyMissingCredentialFromURL(t *testing.T) {
+	// When
+	req := NewRequest("", "explicit-password", "https://urluser@api.com", nil, "")
+
+	// Then
+	if req.Email != "urluser" {
+		t.Errorf(msgFail, "Email filled from URL", "urluser", req.Email)
+	}
+	if req.Password != "explicit-password" {
+		t.Errorf(msgFail, "explicit Password kept", "explicit-password", req.Password)
+	}
+	if req.Url != "https://api.com" {
+		t.Errorf(msgFail, "stripped Url", "https://api.com", req.Url)
+	}
+}
+
+func TestResolveCredentialsFromEnv(t *testing.T) {
+	// Given
+	os.Setenv(envEmail, "env@example.com")
+	os.Setenv(envPassword, "env-password")
+	defer os.Unsetenv(envEmail)
+	defer os.Unsetenv(envPassword)
+
+	// When
+	req := NewRequest("", "", "https://api.com", nil, "")
+
+	// Then
+	if req.Email != "env@example.com" {
+		t.Errorf(msgFail, "env Email", "env@example.com", req.Email)
+	}
+	if req.Password != "env-password" {
+		t.Errorf(msgFail, "env Password", "env-password", req.Password)
+	}
+}
+
+func TestNewRequestFromEnv(t *testing.T) {
+	// Given
+	os.Setenv(envAPIUrl, "https://api.com")
+	os.Setenv(envTokenUrl, "https://api.com/token/")
+	os.Setenv(envToken, "env-token")
+	defer os.Unsetenv(envAPIUrl)
+	defer os.Unsetenv(envTokenUrl)
+	defer os.Unsetenv(envToken)
+
+	// When
+	req, err := NewRequestFromEnv()
+
+	// Then
+	if err != nil {
+		t.Fatalf("NewRequestFromEnv failed: %v", err)
+	}
+	if req.Url != "https://api.com" {
+		t.Errorf(msgFail, "NewRequestFromEnv and Url", "https://api.com", req.Url)
+	}
+	if req.TokenSourceUrl != "https://api.com/token/" {
+		t.Errorf(msgFail, "NewRequestFromEnv and TokenSourceUrl", "https://api.com/token/", req.TokenSourceUrl)
+	}
+	if req.Token == nil || req.Token.Key() != "env-token" {
+		t.Errorf(msgFail, "NewRequestFromEnv and Token", "env-token", req.Token)
+	}
+}
+
+func TestNewRequestFromEnvLoadsCABundle(t *testing.T) {
+	// Given
+	dir := t.TempDir()
+	bundlePath := filepath.Join(dir, "ca.pem")
+	if err := ioutil.WriteFile(bundlePath, []byte(testCaBundle), 0644); err != nil {
+		t.Fatalf("failed to write CA bundle: %v", err)
+	}
+
+	os.Setenv(envAPIUrl, "https://api.com")
+	os.Setenv(envCABundle, bundlePath)
+	defer os.Unsetenv(envAPIUrl)
+	defer os.Unsetenv(envCABundle)
+
+	// When
+	req, err := NewRequestFromEnv()
+
+	// Then
+	if err != nil {
+		t.Fatalf("NewRequestFromEnv failed: %v", err)
+	}
+	if req.CaCerts == nil {
+		t.Error("NewRequestFromEnv should populate CaCerts from CCLIB_CA_BUNDLE")
+	}
+}
+
+func TestNewRequestFromEnvRequiresAPIUrl(t *testing.T) {
+	// Given
+	os.Unsetenv(envAPIUrl)
+
+	// When
+	_, err := NewRequestFromEnv()
+
+	// Then
+	if err == nil {
+		t.Error("NewRequestFromEnv should fail when CCLIB_API_URL is unset")
+	}
+}
@@ -0,0 +1,88 @@
+package cclib
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIError represents a non-2xx response from the cloudControl API
+type APIError struct {
+	StatusCode int
+	Method     string
+	URL        string
+	Body       []byte
+	Message    string
+}
+
+func (err *APIError) Error() string {
+	if err.Message != "" {
+		return fmt.Sprintf("%s %s: %d %s", err.Method, err.URL, err.StatusCode, err.Message)
+	}
+	return fmt.Sprintf("%s %s: %d", err.Method, err.URL, err.StatusCode)
+}
+
+// apiErrorBody is the shape of a cloudControl JSON error response
+type apiErrorBody struct {
+	Message string `json:"error"`
+}
+
+// newAPIError builds an APIError describing resp, decoding body as JSON
+// to populate Message when resp's Content-Type says so
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+	}
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		apiErr.URL = resp.Request.URL.String()
+	}
+
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		var decoded apiErrorBody
+		if err := json.Unmarshal(body, &decoded); err == nil {
+			apiErr.Message = decoded.Message
+		}
+	}
+
+	return apiErr
+}
+
+// IsUnauthorized reports whether err is an *APIError with a 401 status
+func IsUnauthorized(err error) bool {
+	return hasStatus(err, http.StatusUnauthorized)
+}
+
+// IsForbidden reports whether err is an *APIError with a 403 status
+func IsForbidden(err error) bool {
+	return hasStatus(err, http.StatusForbidden)
+}
+
+// IsNotFound reports whether err is an *APIError with a 404 status
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is an *APIError with a 409 status
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsRateLimited reports whether err is an *APIError with a 429 status
+func IsRateLimited(err error) bool {
+	return hasStatus(err, http.StatusTooManyRequests)
+}
+
+// IsServerError reports whether err is an *APIError with a 5xx status
+func IsServerError(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode >= 500
+}
+
+func hasStatus(err error, status int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == status
+}
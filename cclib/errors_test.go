@@ -0,0 +1,62 @@
+package cclib
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckResponseReturnsAPIError(t *testing.T) {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error": "app not found"}`))
+	}))
+	defer server.Close()
+
+	req := NewRequest("", "", server.URL, nil, "")
+
+	// When
+	_, err := req.Get("/app/missing")
+
+	// Then
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected *APIError, got %T: %v", err, err)
+	}
+	if apiErr.StatusCode != http.StatusNotFound {
+		t.Errorf(msgFail, "APIError.StatusCode", http.StatusNotFound, apiErr.StatusCode)
+	}
+	if apiErr.Message != "app not found" {
+		t.Errorf(msgFail, "APIError.Message", "app not found", apiErr.Message)
+	}
+	if !IsNotFound(err) {
+		t.Error("IsNotFound should be true for a 404 response")
+	}
+	if IsForbidden(err) || IsConflict(err) || IsServerError(err) {
+		t.Error("predicates for other statuses should be false for a 404 response")
+	}
+}
+
+func TestStatusPredicates(t *testing.T) {
+	cases := []struct {
+		status    int
+		predicate func(error) bool
+	}{
+		{http.StatusUnauthorized, IsUnauthorized},
+		{http.StatusForbidden, IsForbidden},
+		{http.StatusNotFound, IsNotFound},
+		{http.StatusConflict, IsConflict},
+		{http.StatusTooManyRequests, IsRateLimited},
+		{http.StatusInternalServerError, IsServerError},
+	}
+
+	for _, c := range cases {
+		err := &APIError{StatusCode: c.status}
+		if !c.predicate(err) {
+			t.Errorf("predicate for status %d should be true", c.status)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package cclib
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// PostMultipart makes a POST request with a multipart/form-data body,
+// writing each fields entry as a form field and each files entry (form
+// name -> local path) as an uploaded file
+func (request Request) PostMultipart(resource string, fields url.Values, files map[string]string) ([]byte, error) {
+	return request.doMultipart(resource, "POST", fields, files)
+}
+
+// PutMultipart makes a PUT request with a multipart/form-data body, see
+// PostMultipart
+func (request Request) PutMultipart(resource string, fields url.Values, files map[string]string) ([]byte, error) {
+	return request.doMultipart(resource, "PUT", fields, files)
+}
+
+func (request Request) doMultipart(resource string, method string, fields url.Values, files map[string]string) ([]byte, error) {
+	if request.TokenProvider != nil {
+		token, err := request.TokenProvider.Token(&request)
+		if err != nil {
+			return nil, err
+		}
+		request.Token = token
+	}
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	for key, values := range fields {
+		for _, value := range values {
+			if err := w.WriteField(key, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for fieldName, path := range files {
+		if err := writeFormFile(w, fieldName, path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return request.send(context.Background(), resource, method, buf.Bytes(), w.FormDataContentType(), false)
+}
+
+// writeFormFile opens the file at path and streams its contents into a
+// new form file part named fieldName
+func writeFormFile(w *multipart.Writer, fieldName string, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	part, err := w.CreateFormFile(fieldName, filepath.Base(path))
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(part, file)
+	return err
+}
@@ -0,0 +1,80 @@
+package cclib
+
+import (
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPostMultipart(t *testing.T) {
+	// Given
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "deploy.tar.gz")
+	if err := ioutil.WriteFile(filePath, []byte("bundle contents"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	var gotFieldValue, gotFileContents string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		if err != nil {
+			t.Fatalf("failed to parse Content-Type: %v", err)
+		}
+
+		reader := multipart.NewReader(r.Body, params["boundary"])
+		form, err := reader.ReadForm(1 << 20)
+		if err != nil {
+			t.Fatalf("failed to read multipart form: %v", err)
+		}
+
+		gotFieldValue = form.Value["stack"][0]
+
+		fileHeader := form.File["file"][0]
+		f, err := fileHeader.Open()
+		if err != nil {
+			t.Fatalf("failed to open uploaded file: %v", err)
+		}
+		defer f.Close()
+		contents, _ := ioutil.ReadAll(f)
+		gotFileContents = string(contents)
+	}))
+	defer server.Close()
+
+	req := NewRequest("", "", server.URL, nil, "")
+
+	// When
+	_, err := req.PostMultipart("/app/deployments", url.Values{"stack": {"cedar"}}, map[string]string{"file": filePath})
+
+	// Then
+	if err != nil {
+		t.Fatalf("PostMultipart failed: %v", err)
+	}
+	if gotFieldValue != "cedar" {
+		t.Errorf(msgFail, "PostMultipart and field value", "cedar", gotFieldValue)
+	}
+	if gotFileContents != "bundle contents" {
+		t.Errorf(msgFail, "PostMultipart and file contents", "bundle contents", gotFileContents)
+	}
+}
+
+func TestPostMultipartMissingFile(t *testing.T) {
+	// Given
+	req := NewRequest("", "", "https://api.com", nil, "")
+
+	// When
+	_, err := req.PostMultipart("/app/deployments", url.Values{}, map[string]string{"file": "/no/such/file"})
+
+	// Then
+	if err == nil {
+		t.Error("PostMultipart should fail when a file does not exist")
+	}
+	if !os.IsNotExist(err) {
+		t.Errorf(msgFail, "PostMultipart missing file error", "not exist error", err)
+	}
+}
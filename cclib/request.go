@@ -2,14 +2,17 @@ package cclib
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // Request contains the API request basic information
@@ -23,6 +26,24 @@ type Request struct {
 	Url            string
 	SslCheck       bool
 	CaCerts        *x509.CertPool
+
+	// Timeout bounds the total time send spends on a single call,
+	// including retries. Zero means no timeout.
+	Timeout time.Duration
+
+	// MaxRetries is the number of additional attempts send makes after
+	// a failed call before giving up.
+	MaxRetries int
+
+	// RetryBackoff computes the delay before retry attempt, counting
+	// from 1. A nil RetryBackoff falls back to DefaultRetryBackoff.
+	RetryBackoff func(attempt int) time.Duration
+
+	// TokenProvider, if set, is consulted on every call for the token
+	// to send in place of request.Token. See WithTokenProvider.
+	TokenProvider TokenProvider
+
+	caCertsPEM []byte
 }
 
 // New request creates a new api request having:
@@ -35,7 +56,7 @@ type Request struct {
 //
 // Returns a new request pointer
 func NewRequest(email string, password string, url string, token *Token, tokenSourceUrl string) *Request {
-	return &Request{
+	request := &Request{
 		email,
 		password,
 		token,
@@ -44,7 +65,23 @@ func NewRequest(email string, password string, url string, token *Token, tokenSo
 		CACHE,
 		url,
 		SSL_CHECK,
-		CA_CERTS}
+		CA_CERTS,
+		DefaultTimeout,
+		DefaultMaxRetries,
+		nil,
+		nil,
+		nil}
+	request.ResolveCredentials()
+	return request
+}
+
+// WithTokenProvider sets provider as request's token provider, so that
+// every call authenticates with the token provider returns, refreshed
+// before it goes stale, and returns request for chaining. The stored
+// request.Token itself is not updated.
+func (request *Request) WithTokenProvider(provider TokenProvider) *Request {
+	request.TokenProvider = provider
+	return request
 }
 
 // SetEmail sets email address to a request
@@ -82,9 +119,28 @@ func (request *Request) DisableSSLCheck() {
 	request.SslCheck = false
 }
 
-// SetCaCerts sets a set of root CA to a request
+// SetCaCerts sets a set of root CA to a request. Since caCerts did not
+// come from a PEM bundle gocclib ever saw, this clears any CA bundle
+// snapshot a prior SetCaCertsFromPEM call had stored, so
+// (*Request).BootstrapToken will refuse to render a token from it
+// rather than silently hashing stale or empty data.
 func (request *Request) SetCaCerts(caCerts *x509.CertPool) {
 	request.CaCerts = caCerts
+	request.caCertsPEM = nil
+}
+
+// SetCaCertsFromPEM parses pemBundle into a root CA pool and sets it on
+// request, keeping the PEM bytes alongside it so (*Request).BootstrapToken
+// can later render a token that pins this exact bundle
+func (request *Request) SetCaCertsFromPEM(pemBundle []byte) error {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemBundle) {
+		return errors.New("cclib: no certificates found in CA bundle")
+	}
+
+	request.CaCerts = pool
+	request.caCertsPEM = pemBundle
+	return nil
 }
 
 // Post makes a POST request
@@ -112,7 +168,52 @@ func (request Request) PostToken() ([]byte, error) {
 	return request.do("", "POST", nil, true)
 }
 
+// PostCtx makes a POST request, aborting it if ctx is cancelled
+func (request Request) PostCtx(ctx context.Context, resource string, data url.Values) ([]byte, error) {
+	return request.doCtx(ctx, resource, "POST", data, false)
+}
+
+// GetCtx makes a GET request, aborting it if ctx is cancelled
+func (request Request) GetCtx(ctx context.Context, resource string) ([]byte, error) {
+	return request.doCtx(ctx, resource, "GET", url.Values{}, false)
+}
+
+// PutCtx makes a PUT request, aborting it if ctx is cancelled
+func (request Request) PutCtx(ctx context.Context, resource string, data url.Values) ([]byte, error) {
+	return request.doCtx(ctx, resource, "PUT", data, false)
+}
+
+// DeleteCtx makes a DELETE request, aborting it if ctx is cancelled
+func (request Request) DeleteCtx(ctx context.Context, resource string) ([]byte, error) {
+	return request.doCtx(ctx, resource, "DELETE", url.Values{}, false)
+}
+
 func (request Request) do(resource string, method string, data url.Values, isTokenReq bool) ([]byte, error) {
+	return request.doCtx(context.Background(), resource, method, data, isTokenReq)
+}
+
+func (request Request) doCtx(ctx context.Context, resource string, method string, data url.Values, isTokenReq bool) ([]byte, error) {
+	if !isTokenReq && request.TokenProvider != nil {
+		token, err := request.TokenProvider.Token(&request)
+		if err != nil {
+			return nil, err
+		}
+		request.Token = token
+	}
+	return request.send(ctx, resource, method, []byte(data.Encode()), "application/x-www-form-urlencoded", isTokenReq)
+}
+
+// send builds and executes the actual HTTP request against resource (or
+// the token source URL, if isTokenReq), retrying on network errors and
+// on 5xx/429 responses up to request.MaxRetries times, and giving up
+// early if ctx is done or request.Timeout elapses
+func (request Request) send(ctx context.Context, resource string, method string, body []byte, contentType string, isTokenReq bool) ([]byte, error) {
+	if request.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, request.Timeout)
+		defer cancel()
+	}
+
 	request_url := request.Url
 	if isTokenReq {
 		request_url = request.TokenSourceUrl
@@ -136,46 +237,67 @@ func (request Request) do(resource string, method string, data url.Values, isTok
 	}
 	client := &http.Client{Transport: tr}
 
-	r, err := http.NewRequest(method, urlStr, bytes.NewBufferString(data.Encode()))
-	if err != nil {
-		return nil, err
+	backoff := request.RetryBackoff
+	if backoff == nil {
+		backoff = DefaultRetryBackoff
 	}
 
-	if request.Token != nil {
-		r.Header.Add("Authorization", "cc_auth_token=\""+request.Token.Key()+"\"")
-	} else if request.Email != "" && request.Password != "" {
-		r.SetBasicAuth(request.Email, request.Password)
-	}
-	r.Header.Add("Host", u.Host)
-	r.Header.Add("User-Agent", "gocclib/"+Version())
-	if m := strings.ToUpper(method); m == "POST" || m == "PUT" {
-		r.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-	}
-	r.Header.Add("Content-Length", strconv.Itoa(len(data.Encode())))
-	r.Header.Add("Accept-Encoding", "compress, gzip")
+	for attempt := 0; ; attempt++ {
+		r, err := http.NewRequestWithContext(ctx, method, urlStr, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
 
-	if DEBUG {
-		fmt.Printf("DEBUG Request >>> %v\n", r)
-	}
+		if !isTokenReq && request.Token != nil {
+			r.Header.Add("Authorization", "cc_auth_token=\""+request.Token.Key()+"\"")
+		} else if request.Email != "" && request.Password != "" {
+			r.SetBasicAuth(request.Email, request.Password)
+		}
+		r.Header.Add("Host", u.Host)
+		r.Header.Add("User-Agent", "gocclib/"+Version())
+		if m := strings.ToUpper(method); m == "POST" || m == "PUT" {
+			r.Header.Add("Content-Type", contentType)
+		}
+		r.Header.Add("Content-Length", strconv.Itoa(len(body)))
+		r.Header.Add("Accept-Encoding", "compress, gzip")
 
-	resp, err := client.Do(r)
-	if err != nil {
-		fmt.Printf("DEBUG Request Error >>> %v\n", err)
-		return nil, err
-	}
+		if DEBUG {
+			fmt.Printf("DEBUG Request >>> %v\n", r)
+		}
+
+		resp, err := client.Do(r)
+		if err != nil {
+			if DEBUG {
+				fmt.Printf("DEBUG Request Error >>> %v\n", err)
+			}
+			if attempt >= request.MaxRetries || !sleepForRetry(ctx, backoff(attempt+1)) {
+				return nil, err
+			}
+			continue
+		}
+
+		if isRetryableStatus(resp.StatusCode) && attempt < request.MaxRetries {
+			delay := retryAfterDelay(resp, backoff(attempt+1))
+			resp.Body.Close()
+			if !sleepForRetry(ctx, delay) {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+
+		if err = checkResponse(resp); err != nil {
+			if DEBUG {
+				fmt.Printf("DEBUG Request Error >>> %v\n", err)
+			}
+			return nil, err
+		}
 
-	if err = checkResponse(resp); err != nil {
+		defer resp.Body.Close()
 		if DEBUG {
-			fmt.Printf("DEBUG Request Error >>> %v\n", err)
+			fmt.Printf("DEBUG Response >>> %v\n", resp)
+			fmt.Printf("DEBUG Body >>> %v\n", resp.Body)
 		}
-		return nil, err
-	}
 
-	defer resp.Body.Close()
-	if DEBUG {
-		fmt.Printf("DEBUG Response >>> %v\n", resp)
-		fmt.Printf("DEBUG Body >>> %v\n", resp.Body)
+		return ioutil.ReadAll(resp.Body)
 	}
-
-	return ioutil.ReadAll(resp.Body)
 }
@@ -14,7 +14,7 @@ func TestRequest(t *testing.T) {
 	email := "user@example.com"
 	password := "password"
 	token := &Token{
-		"token": "1234567890",
+		TokenKey: "1234567890",
 	}
 	tokenSourceUrl := "https://api.com/token/"
 	url := "https://api.com"
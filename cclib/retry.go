@@ -0,0 +1,59 @@
+package cclib
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultTimeout is the default value for Request.Timeout
+const DefaultTimeout = 30 * time.Second
+
+// DefaultMaxRetries is the default value for Request.MaxRetries
+const DefaultMaxRetries = 2
+
+// DefaultRetryBackoff is the Request.RetryBackoff used when none is set:
+// a linear 500ms * attempt delay
+func DefaultRetryBackoff(attempt int) time.Duration {
+	return time.Duration(attempt) * 500 * time.Millisecond
+}
+
+// isRetryableStatus reports whether statusCode warrants a retry
+func isRetryableStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// retryAfterDelay returns the delay indicated by resp's Retry-After
+// header, in either its delta-seconds or HTTP-date form (RFC 7231
+// section 7.1.3), falling back to fallback if the header is absent or
+// unparseable
+func retryAfterDelay(resp *http.Response, fallback time.Duration) time.Duration {
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return fallback
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if when, err := http.ParseTime(v); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d
+		}
+		return 0
+	}
+	return fallback
+}
+
+// sleepForRetry waits for d, returning false early if ctx is done first
+func sleepForRetry(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
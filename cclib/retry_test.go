@@ -0,0 +1,127 @@
+package cclib
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSendRetriesOnServerError(t *testing.T) {
+	// Given
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	req := NewRequest("", "", server.URL, nil, "")
+	req.MaxRetries = 2
+	req.RetryBackoff = func(attempt int) time.Duration { return time.Millisecond }
+
+	// When
+	body, err := req.Get("/app")
+
+	// Then
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if string(body) != "ok" {
+		t.Errorf(msgFail, "Get body", "ok", string(body))
+	}
+	if calls != 3 {
+		t.Errorf(msgFail, "retry attempts", 3, calls)
+	}
+}
+
+func TestSendGivesUpAfterMaxRetries(t *testing.T) {
+	// Given
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	req := NewRequest("", "", server.URL, nil, "")
+	req.MaxRetries = 1
+	req.RetryBackoff = func(attempt int) time.Duration { return time.Millisecond }
+
+	// When
+	_, err := req.Get("/app")
+
+	// Then
+	if err == nil {
+		t.Error("Get should fail after exhausting retries")
+	}
+	if calls != 2 {
+		t.Errorf(msgFail, "retry attempts", 2, calls)
+	}
+}
+
+func TestRetryAfterDelaySeconds(t *testing.T) {
+	// Given
+	resp := &http.Response{Header: http.Header{"Retry-After": {"2"}}}
+
+	// When
+	delay := retryAfterDelay(resp, time.Hour)
+
+	// Then
+	if delay != 2*time.Second {
+		t.Errorf(msgFail, "retryAfterDelay seconds form", 2*time.Second, delay)
+	}
+}
+
+func TestRetryAfterDelayHTTPDate(t *testing.T) {
+	// Given
+	when := time.Now().Add(3 * time.Second)
+	resp := &http.Response{Header: http.Header{"Retry-After": {when.UTC().Format(http.TimeFormat)}}}
+
+	// When
+	delay := retryAfterDelay(resp, time.Hour)
+
+	// Then
+	if delay <= 0 || delay > 3*time.Second {
+		t.Errorf(msgFail, "retryAfterDelay HTTP-date form", "~3s", delay)
+	}
+}
+
+func TestRetryAfterDelayFallback(t *testing.T) {
+	// Given
+	resp := &http.Response{Header: http.Header{"Retry-After": {"not-a-valid-value"}}}
+
+	// When
+	delay := retryAfterDelay(resp, 42*time.Second)
+
+	// Then
+	if delay != 42*time.Second {
+		t.Errorf(msgFail, "retryAfterDelay fallback", 42*time.Second, delay)
+	}
+}
+
+func TestGetCtxCancellation(t *testing.T) {
+	// Given
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	req := NewRequest("", "", server.URL, nil, "")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+
+	// When
+	_, err := req.GetCtx(ctx, "/app")
+
+	// Then
+	if err == nil {
+		t.Error("GetCtx should fail once ctx is cancelled")
+	}
+}
@@ -0,0 +1,51 @@
+package cclib
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Token represents an API authentication token together with its
+// expiry, as returned by the cloudControl token source
+type Token struct {
+	TokenKey  string
+	ExpiresAt time.Time
+}
+
+// Key returns the token value used in the Authorization header
+func (token Token) Key() string {
+	return token.TokenKey
+}
+
+// Expired reports whether token is missing, or will expire within skew
+// of the current time
+func (token *Token) Expired(skew time.Duration) bool {
+	if token == nil || token.TokenKey == "" {
+		return true
+	}
+	if token.ExpiresAt.IsZero() {
+		return false
+	}
+	return time.Now().Add(skew).After(token.ExpiresAt)
+}
+
+// tokenResponse mirrors the JSON payload returned by the cloudControl
+// token source
+type tokenResponse struct {
+	Token     string `json:"token"`
+	ExpiresIn int64  `json:"expires_in"`
+}
+
+// parseToken decodes a token source response body into a Token
+func parseToken(body []byte) (*Token, error) {
+	var resp tokenResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, err
+	}
+
+	token := &Token{TokenKey: resp.Token}
+	if resp.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+	}
+	return token, nil
+}
@@ -0,0 +1,62 @@
+package cclib
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultTokenSkew is how far ahead of expiry DefaultTokenProvider
+// considers a token stale
+const DefaultTokenSkew = 30 * time.Second
+
+// TokenProvider supplies a valid Token for a Request, refreshing it as
+// needed. Implementations may cache the token elsewhere, e.g. on disk
+// or in Redis, to share it across processes.
+type TokenProvider interface {
+	// Token returns a valid token for request, refreshing it if
+	// necessary
+	Token(request *Request) (*Token, error)
+}
+
+// DefaultTokenProvider is the TokenProvider shipped with gocclib. A
+// Request uses it only once it is set with WithTokenProvider; a Request
+// with no TokenProvider sends request.Token as is. It keeps a single
+// Token in memory and refreshes it via Request.PostToken once it is
+// missing or within Skew of expiry.
+type DefaultTokenProvider struct {
+	// Skew is how far ahead of expiry a token is considered stale
+	Skew time.Duration
+
+	mu    sync.Mutex
+	token *Token
+}
+
+// NewDefaultTokenProvider returns a DefaultTokenProvider seeded with
+// token, which may be nil
+func NewDefaultTokenProvider(token *Token) *DefaultTokenProvider {
+	return &DefaultTokenProvider{Skew: DefaultTokenSkew, token: token}
+}
+
+// Token returns the cached token, refreshing it via request.PostToken
+// if it is missing or within Skew of expiry
+func (p *DefaultTokenProvider) Token(request *Request) (*Token, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.token.Expired(p.Skew) {
+		return p.token, nil
+	}
+
+	body, err := request.PostToken()
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := parseToken(body)
+	if err != nil {
+		return nil, err
+	}
+
+	p.token = token
+	return p.token, nil
+}
@@ -0,0 +1,85 @@
+package cclib
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDefaultTokenProviderRefreshesWhenMissing(t *testing.T) {
+	// Given
+	var tokenCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token/":
+			atomic.AddInt32(&tokenCalls, 1)
+			fmt.Fprint(w, `{"token": "fresh-token", "expires_in": 3600}`)
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer server.Close()
+
+	req := NewRequest("user@example.com", "password", server.URL, nil, server.URL+"/token/")
+	req.WithTokenProvider(NewDefaultTokenProvider(nil))
+
+	// When
+	body, err := req.Get("/app")
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	_, err = req.Get("/app")
+
+	// Then
+	if err != nil {
+		t.Fatalf("second Get failed: %v", err)
+	}
+	if string(body) != "ok" {
+		t.Errorf(msgFail, "Get body", "ok", string(body))
+	}
+	if tokenCalls != 1 {
+		t.Errorf(msgFail, "token refreshes", 1, tokenCalls)
+	}
+}
+
+func TestDefaultTokenProviderRefreshesNearExpiry(t *testing.T) {
+	// Given
+	var tokenCalls int32
+	var tokenRequestAuth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token/" {
+			atomic.AddInt32(&tokenCalls, 1)
+			tokenRequestAuth = r.Header.Get("Authorization")
+			fmt.Fprint(w, `{"token": "fresh-token", "expires_in": 3600}`)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	staleToken := &Token{TokenKey: "stale-token", ExpiresAt: time.Now().Add(time.Second)}
+	req := NewRequest("user@example.com", "password", server.URL, staleToken, server.URL+"/token/")
+	provider := NewDefaultTokenProvider(staleToken)
+	provider.Skew = time.Minute
+	req.WithTokenProvider(provider)
+
+	// When
+	_, err := req.Get("/app")
+
+	// Then
+	if err != nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if tokenCalls != 1 {
+		t.Errorf(msgFail, "token refreshes", 1, tokenCalls)
+	}
+	// the refresh call must authenticate with Basic Auth, never with the
+	// stale token it is trying to replace
+	if !strings.HasPrefix(tokenRequestAuth, "Basic ") {
+		t.Errorf(msgFail, "token refresh Authorization header", "Basic ...", tokenRequestAuth)
+	}
+}